@@ -8,6 +8,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 type ContractCall struct {
@@ -16,14 +17,38 @@ type ContractCall struct {
 	Method string
 	Args   []interface{}
 	From   common.Address
+
+	// StateOverride overrides account state (balance, nonce, code, storage)
+	// for the duration of this call. It is only applied when the call is
+	// dispatched as an eth_call (e.g. via EthCallParams or MultiCaller's
+	// individual-call fallback), not when packed into a Multicall3 batch.
+	StateOverride StateOverride
+	// BlockOverrides overrides block-level context (number, time, coinbase,
+	// base fee, randao) observed by the call. Subject to the same
+	// eth_call-only restriction as StateOverride.
+	BlockOverrides *BlockOverrides
+
+	// Errors are additional Solidity custom errors DecodeRevert should
+	// recognize beyond those already declared in Abi. This is useful when a
+	// revert can originate from a contract other than the one being called,
+	// e.g. a dispute game factory call reverting with a game's custom error.
+	Errors []abi.Error
+
+	// AllowFailure controls what a revert of this call does to the rest of a
+	// Multicall3 batch it's packed into: if true, the batch continues and
+	// this call's CallResult.Successful reports false; if false, the revert
+	// propagates and aborts the whole aggregate3 request. Has no effect
+	// outside of MultiCaller. Defaults to true via NewContractCall.
+	AllowFailure bool
 }
 
 func NewContractCall(abi *abi.ABI, addr common.Address, method string, args ...interface{}) *ContractCall {
 	return &ContractCall{
-		Abi:    abi,
-		Addr:   addr,
-		Method: method,
-		Args:   args,
+		Abi:          abi,
+		Addr:         addr,
+		Method:       method,
+		Args:         args,
+		AllowFailure: true,
 	}
 }
 
@@ -45,12 +70,31 @@ func (c *ContractCall) ToCallArgs() (interface{}, error) {
 	return arg, nil
 }
 
+// EthCallParams returns the full ordered eth_call parameter list for this
+// call: the call object, the block tag, and — only when set — the state and
+// block overrides attached to it. When only block overrides are set, a nil
+// state override is passed ahead of them to keep the positional order.
+func (c *ContractCall) EthCallParams(block rpc.BlockNumber) ([]interface{}, error) {
+	arg, err := c.ToCallArgs()
+	if err != nil {
+		return nil, err
+	}
+	params := []interface{}{arg, block}
+	if c.StateOverride != nil || c.BlockOverrides != nil {
+		params = append(params, c.StateOverride)
+	}
+	if c.BlockOverrides != nil {
+		params = append(params, c.BlockOverrides)
+	}
+	return params, nil
+}
+
 func (c *ContractCall) Unpack(hex hexutil.Bytes) (*CallResult, error) {
 	out, err := c.Abi.Unpack(c.Method, hex)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unpack data: %w", err)
 	}
-	return &CallResult{out: out}, nil
+	return &CallResult{out: out, success: true, abi: c.Abi, method: c.Method}, nil
 }
 
 func (c *ContractCall) ToTxCandidate() (txmgr.TxCandidate, error) {
@@ -65,49 +109,108 @@ func (c *ContractCall) ToTxCandidate() (txmgr.TxCandidate, error) {
 }
 
 type CallResult struct {
-	out []interface{}
+	out        []interface{}
+	success    bool
+	abi        *abi.ABI
+	method     string
+	revertData hexutil.Bytes
+}
+
+// Successful reports whether the call this result was decoded from succeeded.
+// Results decoded via ContractCall.Unpack directly are always successful;
+// results returned by MultiCaller may be unsuccessful when the underlying
+// call reverted.
+func (c *CallResult) Successful() bool {
+	return c.success
 }
 
+// RevertData returns the raw return data of a failed call, suitable for
+// passing to ContractCall.DecodeRevert to recover a structured custom-error
+// decoding. It is empty for a CallResult where Successful reports true, and
+// may also be empty for a failed call if the RPC client didn't surface the
+// underlying revert bytes.
+func (c *CallResult) RevertData() hexutil.Bytes {
+	return c.revertData
+}
+
+// Len returns the number of output values held by this result.
+func (c *CallResult) Len() int {
+	return len(c.out)
+}
+
+// at returns the raw output value at i, or an error if i is out of range.
+func (c *CallResult) at(i int) (interface{}, error) {
+	if i < 0 || i >= len(c.out) {
+		return nil, fmt.Errorf("index %d out of range: result has %d output values", i, len(c.out))
+	}
+	return c.out[i], nil
+}
+
+// Deprecated: use Get[uint8] for bounds-checked, non-panicking decoding.
 func (c *CallResult) GetUint8(i int) uint8 {
-	return *abi.ConvertType(c.out[i], new(uint8)).(*uint8)
+	return MustGet[uint8](c, i)
 }
 
+// Deprecated: use Get[uint32] for bounds-checked, non-panicking decoding.
 func (c *CallResult) GetUint32(i int) uint32 {
-	return *abi.ConvertType(c.out[i], new(uint32)).(*uint32)
+	return MustGet[uint32](c, i)
 }
 
+// Deprecated: use Get[uint64] for bounds-checked, non-panicking decoding.
 func (c *CallResult) GetUint64(i int) uint64 {
-	return *abi.ConvertType(c.out[i], new(uint64)).(*uint64)
+	return MustGet[uint64](c, i)
 }
 
+// Deprecated: use Get[bool] for bounds-checked, non-panicking decoding.
 func (c *CallResult) GetBool(i int) bool {
-	return *abi.ConvertType(c.out[i], new(bool)).(*bool)
+	return MustGet[bool](c, i)
 }
 
+// Deprecated: use Get[common.Hash] for bounds-checked, non-panicking decoding.
 func (c *CallResult) GetHash(i int) common.Hash {
-	return *abi.ConvertType(c.out[i], new([32]byte)).(*[32]byte)
+	return MustGet[common.Hash](c, i)
 }
 
+// Deprecated: use Get[common.Address] for bounds-checked, non-panicking decoding.
 func (c *CallResult) GetAddress(i int) common.Address {
-	return *abi.ConvertType(c.out[i], new([20]byte)).(*[20]byte)
+	return MustGet[common.Address](c, i)
 }
 
+// Deprecated: use Get[*big.Int] for bounds-checked, non-panicking decoding.
 func (c *CallResult) GetBigInt(i int) *big.Int {
-	return *abi.ConvertType(c.out[i], new(*big.Int)).(**big.Int)
+	return MustGet[*big.Int](c, i)
 }
 
-func (c *CallResult) GetStruct(i int, target interface{}) {
-	abi.ConvertType(c.out[i], target)
+// GetStruct decodes the output at i into target, which must be a pointer to a
+// struct whose fields match the ABI tuple's components.
+//
+// Deprecated: use Get[T] with a concrete struct type T for bounds-checked,
+// non-panicking decoding.
+func (c *CallResult) GetStruct(i int, target interface{}) (err error) {
+	val, err := c.at(i)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to convert output %d to %T: %v", i, target, r)
+		}
+	}()
+	abi.ConvertType(val, target)
+	return nil
 }
 
+// Deprecated: use Get[[]byte] for bounds-checked, non-panicking decoding.
 func (c *CallResult) GetBytes(i int) []byte {
-	return *abi.ConvertType(c.out[i], new([]byte)).(*[]byte)
+	return MustGet[[]byte](c, i)
 }
 
+// Deprecated: use Get[[32]byte] for bounds-checked, non-panicking decoding.
 func (c *CallResult) GetBytes32(i int) [32]byte {
-	return *abi.ConvertType(c.out[i], new([32]byte)).(*[32]byte)
+	return MustGet[[32]byte](c, i)
 }
 
+// Deprecated: use Get[[][32]byte] for bounds-checked, non-panicking decoding.
 func (c *CallResult) GetBytes32Slice(i int) [][32]byte {
-	return *abi.ConvertType(c.out[i], new([][32]byte)).(*[][32]byte)
+	return MustGet[[][32]byte](c, i)
 }
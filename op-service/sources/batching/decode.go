@@ -0,0 +1,83 @@
+package batching
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// Get decodes the output at index i of r into T, validating (when the result
+// carries ABI method information, i.e. it was produced by ContractCall.Unpack)
+// that T is convertible to the type the ABI declares for that output. Unlike
+// the legacy CallResult.GetXxx helpers, Get never panics: out-of-range
+// indices, ABI type mismatches, and conversion failures are all returned as
+// errors.
+//
+// Get supports the same types abi.ConvertType does, including structs for
+// decoding tuples (nested tuples and dynamic arrays included) when T is a
+// struct whose fields match the tuple's components.
+func Get[T any](r *CallResult, i int) (out T, err error) {
+	val, err := r.at(i)
+	if err != nil {
+		return out, err
+	}
+	if r.abi != nil {
+		if err := validateOutputType[T](r.abi, r.method, i); err != nil {
+			return out, err
+		}
+	}
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("method %q output %d: failed to convert to %T: %v", r.method, i, out, rec)
+		}
+	}()
+	abi.ConvertType(val, &out)
+	return out, nil
+}
+
+// MustGet is like Get but panics instead of returning an error. It exists for
+// call sites that already established correctness (e.g. decoding a value that
+// was just ABI-validated) and would rather fail loudly than thread an error
+// that can't realistically occur.
+func MustGet[T any](r *CallResult, i int) T {
+	out, err := Get[T](r, i)
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
+
+// validateOutputType checks that T is a plausible decode target for the i'th
+// declared output of method in a, returning a descriptive error identifying
+// the method, index, and expected-vs-actual type on mismatch.
+func validateOutputType[T any](a *abi.ABI, method string, i int) error {
+	m, ok := a.Methods[method]
+	if !ok {
+		// Nothing to validate against; let the conversion attempt itself fail.
+		return nil
+	}
+	if i < 0 || i >= len(m.Outputs) {
+		return fmt.Errorf("method %q has no output at index %d", method, i)
+	}
+	expected := m.Outputs[i].Type.GetType()
+	var zero T
+	actual := reflect.TypeOf(zero)
+	if actual == nil {
+		// T is an interface type (e.g. `interface{}`); anything decodes into it.
+		return nil
+	}
+	if actual == expected || actual.ConvertibleTo(expected) || expected.ConvertibleTo(actual) {
+		return nil
+	}
+	// A struct holding nested named structs, or a slice or array of structs, is
+	// never convertible to the ABI's generated tuple types, so for these only
+	// the kind (and array length) is checked here; field mismatches surface as
+	// conversion errors from abi.ConvertType instead.
+	if k := actual.Kind(); k == expected.Kind() {
+		if k == reflect.Struct || k == reflect.Slice || (k == reflect.Array && actual.Len() == expected.Len()) {
+			return nil
+		}
+	}
+	return fmt.Errorf("method %q output %d: cannot decode abi type %s into %s", method, i, expected, actual)
+}
@@ -0,0 +1,86 @@
+package batching
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+const testDecodeABIJSON = `[{"inputs":[],"name":"multi","outputs":[{"name":"n","type":"uint256"},{"name":"ok","type":"bool"},{"components":[{"name":"owner","type":"address"},{"name":"amount","type":"uint256"}],"name":"item","type":"tuple"}],"stateMutability":"view","type":"function"}]`
+
+type decodeTestItem struct {
+	Owner  common.Address
+	Amount *big.Int
+}
+
+func mustDecodeTestResult(t *testing.T) *CallResult {
+	t.Helper()
+	a, err := abi.JSON(strings.NewReader(testDecodeABIJSON))
+	require.NoError(t, err)
+	call := NewContractCall(&a, common.Address{}, "multi")
+
+	owner := common.HexToAddress("0x1234")
+	packed, err := a.Methods["multi"].Outputs.Pack(big.NewInt(42), true, struct {
+		Owner  common.Address
+		Amount *big.Int
+	}{owner, big.NewInt(99)})
+	require.NoError(t, err)
+
+	result, err := call.Unpack(packed)
+	require.NoError(t, err)
+	return result
+}
+
+func TestGet_DecodesEachOutput(t *testing.T) {
+	result := mustDecodeTestResult(t)
+	require.Equal(t, 3, result.Len())
+
+	n, err := Get[*big.Int](result, 0)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(42), n)
+
+	ok, err := Get[bool](result, 1)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	item, err := Get[decodeTestItem](result, 2)
+	require.NoError(t, err)
+	require.Equal(t, common.HexToAddress("0x1234"), item.Owner)
+	require.Equal(t, big.NewInt(99), item.Amount)
+}
+
+func TestGet_OutOfRangeIndex(t *testing.T) {
+	result := mustDecodeTestResult(t)
+
+	_, err := Get[*big.Int](result, 3)
+	require.Error(t, err)
+
+	_, err = Get[*big.Int](result, -1)
+	require.Error(t, err)
+}
+
+func TestGet_TypeMismatchIsRejectedBeforeConversion(t *testing.T) {
+	result := mustDecodeTestResult(t)
+
+	// Output 0 is a uint256 (*big.Int); common.Address is neither identical to
+	// nor convertible to it, so this should fail validation rather than
+	// attempt (and panic during) the conversion.
+	_, err := Get[common.Address](result, 0)
+	require.Error(t, err)
+}
+
+func TestMustGet_PanicsOnError(t *testing.T) {
+	result := mustDecodeTestResult(t)
+	require.Panics(t, func() {
+		MustGet[*big.Int](result, 99)
+	})
+}
+
+func TestMustGet_ReturnsValueOnSuccess(t *testing.T) {
+	result := mustDecodeTestResult(t)
+	require.True(t, MustGet[bool](result, 1))
+}
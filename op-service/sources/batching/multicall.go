@@ -0,0 +1,359 @@
+package batching
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// DefaultMultiCallAddr is the canonical Multicall3 deployment address produced
+// by the deterministic deployment proxy. It is identical on every chain that
+// has Multicall3 deployed (https://github.com/mds1/multicall3).
+var DefaultMultiCallAddr = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+// DefaultBatchSize is the number of calls MultiCaller packs into a single
+// aggregate3 request before it starts splitting into additional sub-batches.
+const DefaultBatchSize = 100
+
+// DefaultMaxBatchDataSize is the maximum amount of encoded calldata MultiCaller
+// will pack into a single aggregate3 request before splitting into additional
+// sub-batches, regardless of DefaultBatchSize.
+const DefaultMaxBatchDataSize = 100_000
+
+// DefaultMaxBatchGas is the maximum estimated gas MultiCaller will pack into a
+// single aggregate3 request before splitting into additional sub-batches,
+// regardless of DefaultBatchSize. The estimate is necessarily a lower bound:
+// the actual execution cost of a call can't be known without simulating it,
+// so this only accounts for calldata cost plus a fixed per-call overhead.
+const DefaultMaxBatchGas = 25_000_000
+
+// txDataNonZeroGas and txDataZeroGas are the EIP-2028 per-byte calldata gas
+// costs used to estimate a call's contribution to a batch's gas ceiling.
+const (
+	txDataNonZeroGas = 16
+	txDataZeroGas    = 4
+	// callOverheadGas is a conservative per-call fixed cost (CALL plus
+	// ABI-encoding overhead for the outer aggregate3 call) added on top of
+	// calldata cost when estimating a call's gas contribution to a batch.
+	callOverheadGas = 21_000
+)
+
+const multicall3ABIJSON = `[{"inputs":[{"components":[{"name":"target","type":"address"},{"name":"allowFailure","type":"bool"},{"name":"callData","type":"bytes"}],"name":"calls","type":"tuple[]"}],"name":"aggregate3","outputs":[{"components":[{"name":"success","type":"bool"},{"name":"returnData","type":"bytes"}],"name":"returnData","type":"tuple[]"}],"stateMutability":"payable","type":"function"}]`
+
+var multicall3ABI abi.ABI
+
+func init() {
+	parsed, err := abi.JSON(strings.NewReader(multicall3ABIJSON))
+	if err != nil {
+		panic(fmt.Errorf("failed to parse multicall3 abi: %w", err))
+	}
+	multicall3ABI = parsed
+}
+
+// call3 mirrors the Multicall3.Call3 solidity struct.
+type call3 struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+// result3 mirrors the Multicall3.Result solidity struct.
+type result3 struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// Caller is the subset of an RPC client MultiCaller needs to dispatch
+// eth_call and eth_getCode requests. *rpc.Client satisfies it.
+type Caller interface {
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+}
+
+// MultiCaller batches ContractCalls into aggregate3 requests against a
+// Multicall3 deployment, dispatching a single eth_call per batch instead of
+// one eth_call per ContractCall. If no Multicall3 contract is deployed on the
+// target chain, it transparently falls back to issuing individual eth_calls.
+type MultiCaller struct {
+	multicall3Addr   common.Address
+	batchSize        int
+	maxBatchDataSize int
+	maxBatchGas      int
+}
+
+// NewMultiCaller creates a MultiCaller targeting the Multicall3 deployment at
+// multicall3Addr, splitting requests into sub-batches of at most batchSize
+// calls each.
+func NewMultiCaller(multicall3Addr common.Address, batchSize int) *MultiCaller {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	return &MultiCaller{
+		multicall3Addr:   multicall3Addr,
+		batchSize:        batchSize,
+		maxBatchDataSize: DefaultMaxBatchDataSize,
+		maxBatchGas:      DefaultMaxBatchGas,
+	}
+}
+
+// WithMaxBatchDataSize overrides the default ceiling on encoded calldata size
+// per aggregate3 request, returning the MultiCaller for chaining.
+func (m *MultiCaller) WithMaxBatchDataSize(maxBatchDataSize int) *MultiCaller {
+	m.maxBatchDataSize = maxBatchDataSize
+	return m
+}
+
+// WithMaxBatchGas overrides the default ceiling on estimated gas per
+// aggregate3 request, returning the MultiCaller for chaining.
+func (m *MultiCaller) WithMaxBatchGas(maxBatchGas int) *MultiCaller {
+	m.maxBatchGas = maxBatchGas
+	return m
+}
+
+// Call executes calls against the configured Multicall3 deployment, batching
+// as many as possible into each aggregate3 round-trip, and returns one
+// *CallResult per call in the same order calls were supplied. If no
+// Multicall3 contract is deployed at multicall3Addr, Call falls back to
+// issuing one eth_call per ContractCall. Calls with a StateOverride or
+// BlockOverrides set are always routed through that individual-call fallback:
+// aggregate3 has no way to apply per-call state/block overrides, so batching
+// them would silently simulate against real chain state instead.
+func (m *MultiCaller) Call(ctx context.Context, caller Caller, block rpc.BlockNumber, calls ...*ContractCall) ([]*CallResult, error) {
+	if len(calls) == 0 {
+		return nil, nil
+	}
+	deployed, err := m.isDeployed(ctx, caller, block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for multicall3 deployment at %s: %w", m.multicall3Addr, err)
+	}
+	if !deployed {
+		return m.callIndividually(ctx, caller, block, calls)
+	}
+
+	results := make([]*CallResult, len(calls))
+	var batchable, individual []*ContractCall
+	var batchableIdx, individualIdx []int
+	for i, c := range calls {
+		if c.StateOverride != nil || c.BlockOverrides != nil {
+			individual = append(individual, c)
+			individualIdx = append(individualIdx, i)
+		} else {
+			batchable = append(batchable, c)
+			batchableIdx = append(batchableIdx, i)
+		}
+	}
+
+	if len(individual) > 0 {
+		individualResults, err := m.callIndividually(ctx, caller, block, individual)
+		if err != nil {
+			return nil, err
+		}
+		for j, idx := range individualIdx {
+			results[idx] = individualResults[j]
+		}
+	}
+	for _, batch := range m.splitBatches(batchable) {
+		batchResults, err := m.callBatch(ctx, caller, block, batch.calls)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute batch [%d:%d]: %w", batch.start, batch.start+len(batch.calls), err)
+		}
+		for j, r := range batchResults {
+			results[batchableIdx[batch.start+j]] = r
+		}
+	}
+	return results, nil
+}
+
+// isDeployed checks whether the configured Multicall3 address has contract
+// code on the target chain, so Call can fall back to individual eth_calls
+// when it doesn't.
+func (m *MultiCaller) isDeployed(ctx context.Context, caller Caller, block rpc.BlockNumber) (bool, error) {
+	var code hexutil.Bytes
+	if err := caller.CallContext(ctx, &code, "eth_getCode", m.multicall3Addr, block); err != nil {
+		return false, err
+	}
+	return len(code) > 0, nil
+}
+
+type callBatch struct {
+	start int
+	calls []*ContractCall
+}
+
+// splitBatches groups calls into sub-batches that respect the configured
+// batchSize, maxBatchDataSize, and maxBatchGas ceilings.
+func (m *MultiCaller) splitBatches(calls []*ContractCall) []callBatch {
+	var batches []callBatch
+	start := 0
+	for start < len(calls) {
+		end := start
+		dataSize := 0
+		gas := 0
+		for end < len(calls) && end-start < m.batchSize {
+			data, err := calls[end].Pack()
+			if err != nil {
+				// Packing errors surface when the batch is actually dispatched.
+				end++
+				continue
+			}
+			callGas := estimateCallGas(data)
+			if end > start && (dataSize+len(data) > m.maxBatchDataSize || gas+callGas > m.maxBatchGas) {
+				break
+			}
+			dataSize += len(data)
+			gas += callGas
+			end++
+		}
+		if end == start {
+			// Not reached while batchSize > 0: the loop above always takes the
+			// first call, so a lone call exceeding maxBatchDataSize/maxBatchGas
+			// is already sent alone. This only guards against an empty batch.
+			end = start + 1
+		}
+		batches = append(batches, callBatch{start: start, calls: calls[start:end]})
+		start = end
+	}
+	return batches
+}
+
+// estimateCallGas returns a lower-bound gas estimate for packing data into an
+// aggregate3 batch: the calldata cost of data plus a fixed per-call overhead.
+// This can't account for the call's actual execution cost, which is unknown
+// without simulating it, so it only guards against batches whose calldata
+// alone would already blow the gas ceiling.
+func estimateCallGas(data []byte) int {
+	gas := callOverheadGas
+	for _, b := range data {
+		if b == 0 {
+			gas += txDataZeroGas
+		} else {
+			gas += txDataNonZeroGas
+		}
+	}
+	return gas
+}
+
+// callBatch packs calls into a single aggregate3 request and decodes the
+// response into one *CallResult per call, in order.
+func (m *MultiCaller) callBatch(ctx context.Context, caller Caller, block rpc.BlockNumber, calls []*ContractCall) ([]*CallResult, error) {
+	call3s := make([]call3, len(calls))
+	for i, c := range calls {
+		data, err := c.Pack()
+		if err != nil {
+			return nil, fmt.Errorf("failed to pack call %d: %w", i, err)
+		}
+		call3s[i] = call3{Target: c.Addr, AllowFailure: c.AllowFailure, CallData: data}
+	}
+	input, err := multicall3ABI.Pack("aggregate3", call3s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack aggregate3 call: %w", err)
+	}
+	args := map[string]interface{}{
+		"to":    &m.multicall3Addr,
+		"input": hexutil.Bytes(input),
+	}
+	var raw hexutil.Bytes
+	if err := caller.CallContext(ctx, &raw, "eth_call", args, block); err != nil {
+		return nil, fmt.Errorf("aggregate3 eth_call failed: %w", err)
+	}
+	out, err := multicall3ABI.Unpack("aggregate3", raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack aggregate3 result: %w", err)
+	}
+	if len(out) != 1 {
+		return nil, fmt.Errorf("unexpected aggregate3 output count: %d", len(out))
+	}
+	rawResults, err := decodeAggregate3Results(out[0])
+	if err != nil {
+		return nil, fmt.Errorf("unexpected aggregate3 output: %w", err)
+	}
+	if len(rawResults) != len(calls) {
+		return nil, fmt.Errorf("aggregate3 returned %d results for %d calls", len(rawResults), len(calls))
+	}
+	results := make([]*CallResult, len(calls))
+	for i, r := range rawResults {
+		if !r.Success {
+			results[i] = &CallResult{success: false, revertData: r.ReturnData}
+			continue
+		}
+		result, err := calls[i].Unpack(r.ReturnData)
+		if err != nil {
+			results[i] = &CallResult{success: false, revertData: r.ReturnData}
+			continue
+		}
+		result.success = true
+		results[i] = result
+	}
+	return results, nil
+}
+
+// decodeAggregate3Results converts an aggregate3 output value into []result3.
+// abi.Unpack builds the tuple[] output as a slice of a dynamically generated,
+// struct-tagged type (see accounts/abi/type.go's NewType),
+// so a plain type assertion to []result3 never succeeds even though the
+// fields line up; abi.ConvertType performs the reflect-based conversion that
+// actually works here, the same way decode.go's Get does for single values.
+func decodeAggregate3Results(out interface{}) (results []result3, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to convert %T to []result3: %v", out, r)
+		}
+	}()
+	abi.ConvertType(out, &results)
+	return results, nil
+}
+
+// callIndividually is the fallback path used when no Multicall3 deployment is
+// found on the target chain: it issues one eth_call per ContractCall.
+func (m *MultiCaller) callIndividually(ctx context.Context, caller Caller, block rpc.BlockNumber, calls []*ContractCall) ([]*CallResult, error) {
+	results := make([]*CallResult, len(calls))
+	for i, c := range calls {
+		params, err := c.EthCallParams(block)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build call args for call %d: %w", i, err)
+		}
+		var raw hexutil.Bytes
+		if err := caller.CallContext(ctx, &raw, "eth_call", params...); err != nil {
+			results[i] = &CallResult{success: false, revertData: revertDataFromError(err)}
+			continue
+		}
+		result, err := c.Unpack(raw)
+		if err != nil {
+			results[i] = &CallResult{success: false, revertData: raw}
+			continue
+		}
+		result.success = true
+		results[i] = result
+	}
+	return results, nil
+}
+
+// revertDataFromError extracts revert data from a failed eth_call's error, if
+// the RPC client surfaced it. go-ethereum's rpc.Client wraps a reverted call
+// in an error implementing rpc.DataError, whose ErrorData carries the raw
+// revert bytes — typically hex-encoded, occasionally already raw bytes.
+func revertDataFromError(err error) hexutil.Bytes {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil
+	}
+	switch data := dataErr.ErrorData().(type) {
+	case string:
+		b, decodeErr := hexutil.Decode(data)
+		if decodeErr != nil {
+			return nil
+		}
+		return b
+	case []byte:
+		return data
+	case hexutil.Bytes:
+		return data
+	default:
+		return nil
+	}
+}
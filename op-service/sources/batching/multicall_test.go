@@ -0,0 +1,321 @@
+package batching
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+const testFooABIJSON = `[{"inputs":[],"name":"foo","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`
+
+// fakeAggregate3Caller answers eth_call with a canned aggregate3 response,
+// built by packing real Multicall3 Result structs through multicall3ABI -
+// i.e. the same bytes a live Multicall3 deployment would return.
+type fakeAggregate3Caller struct {
+	results []result3
+}
+
+func (f *fakeAggregate3Caller) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if method != "eth_call" {
+		panic("unexpected method " + method)
+	}
+	packed, err := multicall3ABI.Methods["aggregate3"].Outputs.Pack(toCall3Results(f.results))
+	if err != nil {
+		return err
+	}
+	*(result.(*hexutil.Bytes)) = packed
+	return nil
+}
+
+// toCall3Results re-shapes []result3 into the anonymous struct layout
+// multicall3ABI.Pack's reflection expects for a tuple[] argument.
+func toCall3Results(results []result3) []struct {
+	Success    bool
+	ReturnData []byte
+} {
+	out := make([]struct {
+		Success    bool
+		ReturnData []byte
+	}, len(results))
+	for i, r := range results {
+		out[i] = struct {
+			Success    bool
+			ReturnData []byte
+		}{Success: r.Success, ReturnData: r.ReturnData}
+	}
+	return out
+}
+
+// TestMultiCaller_callBatch_DecodesAggregate3Result guards against a
+// regression where decoding a real aggregate3 response off of an
+// abi.Unpack'd interface{} via a type assertion silently always fails: the
+// dynamic type abi.Unpack produces carries json struct tags that a bare
+// assertion can never match, even though the fields line up.
+func TestMultiCaller_callBatch_DecodesAggregate3Result(t *testing.T) {
+	fooABI, err := abi.JSON(strings.NewReader(testFooABIJSON))
+	require.NoError(t, err)
+
+	target := common.HexToAddress("0x1234")
+	calls := []*ContractCall{
+		NewContractCall(&fooABI, target, "foo"),
+		NewContractCall(&fooABI, target, "foo"),
+	}
+
+	okReturnData, err := fooABI.Methods["foo"].Outputs.Pack(big.NewInt(42))
+	require.NoError(t, err)
+
+	caller := &fakeAggregate3Caller{results: []result3{
+		{Success: true, ReturnData: okReturnData},
+		{Success: false, ReturnData: nil},
+	}}
+
+	m := NewMultiCaller(DefaultMultiCallAddr, DefaultBatchSize)
+	results, err := m.callBatch(context.Background(), caller, rpc.LatestBlockNumber, calls)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	require.True(t, results[0].Successful())
+	require.Equal(t, big.NewInt(42), MustGet[*big.Int](results[0], 0))
+
+	require.False(t, results[1].Successful())
+}
+
+// recordingCaller is a Caller that serves eth_getCode from a fixed code blob,
+// answers eth_call to multicall3Addr with a canned aggregate3 response, and
+// answers any other eth_call "to" with fooReturn — while recording the "to"
+// address of every eth_call it receives, so tests can assert which calls went
+// through aggregate3 batching versus direct individual dispatch.
+type recordingCaller struct {
+	multicall3Addr    common.Address
+	deployed          bool
+	fooReturn         []byte
+	aggregate3Results []result3
+	calls             []common.Address
+}
+
+func (f *recordingCaller) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	switch method {
+	case "eth_getCode":
+		if f.deployed {
+			*(result.(*hexutil.Bytes)) = hexutil.Bytes{0x1}
+		}
+		return nil
+	case "eth_call":
+		callArgs := args[0].(map[string]interface{})
+		to := *(callArgs["to"].(*common.Address))
+		f.calls = append(f.calls, to)
+		if to == f.multicall3Addr {
+			packed, err := multicall3ABI.Methods["aggregate3"].Outputs.Pack(toCall3Results(f.aggregate3Results))
+			if err != nil {
+				return err
+			}
+			*(result.(*hexutil.Bytes)) = packed
+			return nil
+		}
+		*(result.(*hexutil.Bytes)) = f.fooReturn
+		return nil
+	default:
+		panic("unexpected method " + method)
+	}
+}
+
+// TestMultiCaller_Call_RoutesOverridesAroundBatching guards against a
+// regression where a ContractCall with a StateOverride or BlockOverrides set
+// was silently packed into the same aggregate3 batch as everything else,
+// discarding the override instead of applying it.
+func TestMultiCaller_Call_RoutesOverridesAroundBatching(t *testing.T) {
+	fooABI, err := abi.JSON(strings.NewReader(testFooABIJSON))
+	require.NoError(t, err)
+	target := common.HexToAddress("0x1234")
+
+	plain := NewContractCall(&fooABI, target, "foo")
+	overridden := NewContractCall(&fooABI, target, "foo")
+	overridden.StateOverride = StateOverride{target: &AccountOverride{}}
+
+	fooReturn, err := fooABI.Methods["foo"].Outputs.Pack(big.NewInt(1))
+	require.NoError(t, err)
+
+	caller := &recordingCaller{
+		multicall3Addr:    DefaultMultiCallAddr,
+		deployed:          true,
+		fooReturn:         fooReturn,
+		aggregate3Results: []result3{{Success: true, ReturnData: fooReturn}},
+	}
+
+	m := NewMultiCaller(DefaultMultiCallAddr, DefaultBatchSize)
+	results, err := m.Call(context.Background(), caller, rpc.LatestBlockNumber, plain, overridden)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.True(t, results[0].Successful())
+	require.True(t, results[1].Successful())
+
+	var toMulticall, toTarget int
+	for _, to := range caller.calls {
+		switch to {
+		case DefaultMultiCallAddr:
+			toMulticall++
+		case target:
+			toTarget++
+		}
+	}
+	require.Equal(t, 1, toMulticall, "the plain call should have gone through the aggregate3 batch")
+	require.Equal(t, 1, toTarget, "the overridden call should have bypassed batching and hit the target directly")
+}
+
+// TestMultiCaller_Call_FallsBackWhenNotDeployed covers callIndividually's use
+// as Call's fallback when no Multicall3 contract is found on chain.
+func TestMultiCaller_Call_FallsBackWhenNotDeployed(t *testing.T) {
+	fooABI, err := abi.JSON(strings.NewReader(testFooABIJSON))
+	require.NoError(t, err)
+	target := common.HexToAddress("0x1234")
+	call := NewContractCall(&fooABI, target, "foo")
+
+	fooReturn, err := fooABI.Methods["foo"].Outputs.Pack(big.NewInt(1))
+	require.NoError(t, err)
+
+	caller := &recordingCaller{multicall3Addr: DefaultMultiCallAddr, deployed: false, fooReturn: fooReturn}
+	m := NewMultiCaller(DefaultMultiCallAddr, DefaultBatchSize)
+	results, err := m.Call(context.Background(), caller, rpc.LatestBlockNumber, call)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.True(t, results[0].Successful())
+	require.Equal(t, []common.Address{target}, caller.calls)
+}
+
+func TestMultiCaller_splitBatches_RespectsBatchSize(t *testing.T) {
+	fooABI, err := abi.JSON(strings.NewReader(testFooABIJSON))
+	require.NoError(t, err)
+	target := common.HexToAddress("0x1234")
+
+	calls := make([]*ContractCall, 5)
+	for i := range calls {
+		calls[i] = NewContractCall(&fooABI, target, "foo")
+	}
+
+	m := NewMultiCaller(DefaultMultiCallAddr, 2)
+	batches := m.splitBatches(calls)
+	require.Len(t, batches, 3)
+	require.Equal(t, callBatch{start: 0, calls: calls[0:2]}, batches[0])
+	require.Equal(t, callBatch{start: 2, calls: calls[2:4]}, batches[1])
+	require.Equal(t, callBatch{start: 4, calls: calls[4:5]}, batches[2])
+}
+
+func TestMultiCaller_splitBatches_RespectsMaxBatchDataSize(t *testing.T) {
+	fooABI, err := abi.JSON(strings.NewReader(testFooABIJSON))
+	require.NoError(t, err)
+	target := common.HexToAddress("0x1234")
+
+	calls := make([]*ContractCall, 3)
+	for i := range calls {
+		calls[i] = NewContractCall(&fooABI, target, "foo")
+	}
+	data, err := calls[0].Pack()
+	require.NoError(t, err)
+
+	// A ceiling equal to exactly one call's calldata forces every call into
+	// its own batch.
+	m := NewMultiCaller(DefaultMultiCallAddr, DefaultBatchSize).WithMaxBatchDataSize(len(data))
+	batches := m.splitBatches(calls)
+	require.Len(t, batches, 3)
+	for _, b := range batches {
+		require.Len(t, b.calls, 1)
+	}
+}
+
+func TestMultiCaller_splitBatches_RespectsMaxBatchGas(t *testing.T) {
+	fooABI, err := abi.JSON(strings.NewReader(testFooABIJSON))
+	require.NoError(t, err)
+	target := common.HexToAddress("0x1234")
+
+	calls := make([]*ContractCall, 3)
+	for i := range calls {
+		calls[i] = NewContractCall(&fooABI, target, "foo")
+	}
+	data, err := calls[0].Pack()
+	require.NoError(t, err)
+	gas := estimateCallGas(data)
+
+	// A ceiling equal to exactly one call's estimated gas forces every call
+	// into its own batch.
+	m := NewMultiCaller(DefaultMultiCallAddr, DefaultBatchSize).WithMaxBatchGas(gas)
+	batches := m.splitBatches(calls)
+	require.Len(t, batches, 3)
+	for _, b := range batches {
+		require.Len(t, b.calls, 1)
+	}
+}
+
+func TestMultiCaller_splitBatches_SingleOversizedCallGoesAlone(t *testing.T) {
+	fooABI, err := abi.JSON(strings.NewReader(testFooABIJSON))
+	require.NoError(t, err)
+	target := common.HexToAddress("0x1234")
+	calls := []*ContractCall{NewContractCall(&fooABI, target, "foo")}
+
+	// A ceiling of 1 byte is smaller than any real call's data, but a lone
+	// call must still be sent rather than dropped.
+	m := NewMultiCaller(DefaultMultiCallAddr, DefaultBatchSize).WithMaxBatchDataSize(1)
+	batches := m.splitBatches(calls)
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0].calls, 1)
+}
+
+// fakeDataError simulates an eth_call revert error as surfaced by
+// go-ethereum's rpc.Client, which implements rpc.DataError.
+type fakeDataError struct {
+	msg  string
+	data interface{}
+}
+
+func (e *fakeDataError) Error() string          { return e.msg }
+func (e *fakeDataError) ErrorData() interface{} { return e.data }
+
+// erroringCaller always fails eth_call with a fixed error, simulating a
+// reverted individual eth_call.
+type erroringCaller struct {
+	err error
+}
+
+func (c *erroringCaller) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return c.err
+}
+
+func TestMultiCaller_callBatch_SurfacesRevertDataOnFailure(t *testing.T) {
+	fooABI, err := abi.JSON(strings.NewReader(testFooABIJSON))
+	require.NoError(t, err)
+	target := common.HexToAddress("0x1234")
+	call := NewContractCall(&fooABI, target, "foo")
+
+	revertData := []byte{0xde, 0xad, 0xbe, 0xef}
+	caller := &fakeAggregate3Caller{results: []result3{{Success: false, ReturnData: revertData}}}
+
+	m := NewMultiCaller(DefaultMultiCallAddr, DefaultBatchSize)
+	results, err := m.callBatch(context.Background(), caller, rpc.LatestBlockNumber, []*ContractCall{call})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.False(t, results[0].Successful())
+	require.Equal(t, hexutil.Bytes(revertData), results[0].RevertData())
+}
+
+func TestMultiCaller_callIndividually_SurfacesRevertDataFromRPCError(t *testing.T) {
+	fooABI, err := abi.JSON(strings.NewReader(testFooABIJSON))
+	require.NoError(t, err)
+	target := common.HexToAddress("0x1234")
+	call := NewContractCall(&fooABI, target, "foo")
+
+	revertData := hexutil.Bytes{0xca, 0xfe}
+	caller := &erroringCaller{err: &fakeDataError{msg: "execution reverted", data: revertData.String()}}
+
+	m := NewMultiCaller(DefaultMultiCallAddr, DefaultBatchSize)
+	results, err := m.callIndividually(context.Background(), caller, rpc.LatestBlockNumber, []*ContractCall{call})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.False(t, results[0].Successful())
+	require.Equal(t, revertData, results[0].RevertData())
+}
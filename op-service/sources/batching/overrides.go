@@ -0,0 +1,35 @@
+package batching
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// AccountOverride overrides the state of a single account for the duration of
+// an eth_call, mirroring the shape geth's eth_call state-override parameter
+// expects.
+type AccountOverride struct {
+	Balance   *hexutil.Big                `json:"balance,omitempty"`
+	Nonce     *hexutil.Uint64             `json:"nonce,omitempty"`
+	Code      *hexutil.Bytes              `json:"code,omitempty"`
+	State     map[common.Hash]common.Hash `json:"state,omitempty"`
+	StateDiff map[common.Hash]common.Hash `json:"stateDiff,omitempty"`
+}
+
+// StateOverride maps accounts to the overrides applied to their state for the
+// duration of an eth_call.
+type StateOverride map[common.Address]*AccountOverride
+
+// BlockOverrides overrides block-level context (e.g. the block number or
+// timestamp a contract observes via NUMBER/TIMESTAMP) for the duration of an
+// eth_call. Field names and JSON keys match internal/ethapi.BlockOverrides in
+// the pinned go-ethereum version, which is what the target node actually
+// unmarshals this into; in particular PREVRANDAO is exposed as Random/"random",
+// not "prevRandao".
+type BlockOverrides struct {
+	Number   *hexutil.Big    `json:"number,omitempty"`
+	Time     *hexutil.Uint64 `json:"time,omitempty"`
+	Coinbase *common.Address `json:"coinbase,omitempty"`
+	BaseFee  *hexutil.Big    `json:"baseFee,omitempty"`
+	Random   *common.Hash    `json:"random,omitempty"`
+}
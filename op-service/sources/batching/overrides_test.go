@@ -0,0 +1,74 @@
+package batching
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAccountOverride_JSONKeys guards the wire shape of AccountOverride
+// against the kind of field-name mismatch that would make geth silently
+// ignore an override.
+func TestAccountOverride_JSONKeys(t *testing.T) {
+	balance := (*hexutil.Big)(big.NewInt(100))
+	nonce := hexutil.Uint64(1)
+	code := hexutil.Bytes{0xde, 0xad}
+	override := AccountOverride{
+		Balance:   balance,
+		Nonce:     &nonce,
+		Code:      &code,
+		State:     map[common.Hash]common.Hash{common.HexToHash("0x1"): common.HexToHash("0x2")},
+		StateDiff: map[common.Hash]common.Hash{common.HexToHash("0x3"): common.HexToHash("0x4")},
+	}
+
+	raw, err := json.Marshal(override)
+	require.NoError(t, err)
+
+	var asMap map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(raw, &asMap))
+	for _, key := range []string{"balance", "nonce", "code", "state", "stateDiff"} {
+		require.Containsf(t, asMap, key, "expected wire key %q", key)
+	}
+}
+
+func TestAccountOverride_OmitsUnsetFields(t *testing.T) {
+	raw, err := json.Marshal(AccountOverride{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{}`, string(raw))
+}
+
+// TestBlockOverrides_JSONKeys guards the wire shape of BlockOverrides against
+// a regression like the PrevRandao/"prevRandao" mismatch fixed previously:
+// the target node expects the randao value under "random".
+func TestBlockOverrides_JSONKeys(t *testing.T) {
+	random := common.HexToHash("0xabc")
+	coinbase := common.HexToAddress("0xdef")
+	blockTime := hexutil.Uint64(123)
+	overrides := BlockOverrides{
+		Number:   (*hexutil.Big)(big.NewInt(10)),
+		Time:     &blockTime,
+		Coinbase: &coinbase,
+		BaseFee:  (*hexutil.Big)(big.NewInt(1)),
+		Random:   &random,
+	}
+
+	raw, err := json.Marshal(overrides)
+	require.NoError(t, err)
+
+	var asMap map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(raw, &asMap))
+	for _, key := range []string{"number", "time", "coinbase", "baseFee", "random"} {
+		require.Containsf(t, asMap, key, "expected wire key %q", key)
+	}
+	require.NotContains(t, asMap, "prevRandao")
+}
+
+func TestBlockOverrides_OmitsUnsetFields(t *testing.T) {
+	raw, err := json.Marshal(BlockOverrides{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{}`, string(raw))
+}
@@ -0,0 +1,57 @@
+package batching
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// RevertError is a structured decoding of a Solidity custom error returned by
+// a reverted call, as an alternative to the opaque hex blob eth_call returns
+// on revert.
+type RevertError struct {
+	Name string
+	Args []interface{}
+	Raw  hexutil.Bytes
+}
+
+func (e *RevertError) Error() string {
+	return fmt.Sprintf("execution reverted: %s%v", e.Name, e.Args)
+}
+
+// DecodeRevert matches the 4-byte selector at the start of data against the
+// call's own ABI errors plus any additional Errors attached to the
+// ContractCall — useful when the revert can originate from a contract other
+// than the one being called, e.g. a dispute game factory call propagating a
+// game's custom error such as GameNotInProgress(...) — and returns a
+// *RevertError describing the match. If no custom error matches, a plain error
+// naming the method and the unrecognized selector is returned.
+func (c *ContractCall) DecodeRevert(data hexutil.Bytes) error {
+	if len(data) < 4 {
+		return fmt.Errorf("revert data too short to contain a selector: %x", []byte(data))
+	}
+	selector := data[:4]
+	for _, candidate := range c.candidateErrors() {
+		if !bytes.Equal(candidate.ID[:4], selector) {
+			continue
+		}
+		args, err := candidate.Inputs.Unpack(data[4:])
+		if err != nil {
+			return fmt.Errorf("failed to unpack revert args for %s: %w", candidate.Name, err)
+		}
+		return &RevertError{Name: candidate.Name, Args: args, Raw: data}
+	}
+	return fmt.Errorf("call to %s reverted with unrecognized selector %x", c.Method, selector)
+}
+
+// candidateErrors returns every custom error DecodeRevert should try to match
+// against: those declared in the call's own ABI, plus any attached via Errors.
+func (c *ContractCall) candidateErrors() []abi.Error {
+	errs := make([]abi.Error, 0, len(c.Abi.Errors)+len(c.Errors))
+	for _, e := range c.Abi.Errors {
+		errs = append(errs, e)
+	}
+	return append(errs, c.Errors...)
+}
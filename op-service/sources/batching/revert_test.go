@@ -0,0 +1,76 @@
+package batching
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+const testRevertABIJSON = `[{"inputs":[],"name":"foo","outputs":[],"stateMutability":"view","type":"function"},{"inputs":[{"name":"x","type":"uint256"}],"name":"CustomError","type":"error"}]`
+
+const testExternalErrorABIJSON = `[{"inputs":[{"name":"reason","type":"string"}],"name":"GameNotInProgress","type":"error"}]`
+
+func TestContractCall_DecodeRevert_MatchesOwnABIError(t *testing.T) {
+	a, err := abi.JSON(strings.NewReader(testRevertABIJSON))
+	require.NoError(t, err)
+	call := NewContractCall(&a, common.Address{}, "foo")
+
+	customErr := a.Errors["CustomError"]
+	packed, err := customErr.Inputs.Pack(big.NewInt(7))
+	require.NoError(t, err)
+	data := append(append([]byte{}, customErr.ID[:4]...), packed...)
+
+	err = call.DecodeRevert(data)
+	var revertErr *RevertError
+	require.ErrorAs(t, err, &revertErr)
+	require.Equal(t, "CustomError", revertErr.Name)
+	require.Equal(t, []interface{}{big.NewInt(7)}, revertErr.Args)
+	require.Equal(t, hexutil.Bytes(data), revertErr.Raw)
+}
+
+func TestContractCall_DecodeRevert_MatchesAttachedError(t *testing.T) {
+	callABI, err := abi.JSON(strings.NewReader(testRevertABIJSON))
+	require.NoError(t, err)
+	externalABI, err := abi.JSON(strings.NewReader(testExternalErrorABIJSON))
+	require.NoError(t, err)
+
+	call := NewContractCall(&callABI, common.Address{}, "foo")
+	call.Errors = []abi.Error{externalABI.Errors["GameNotInProgress"]}
+
+	gameErr := externalABI.Errors["GameNotInProgress"]
+	packed, err := gameErr.Inputs.Pack("not started")
+	require.NoError(t, err)
+	data := append(append([]byte{}, gameErr.ID[:4]...), packed...)
+
+	err = call.DecodeRevert(data)
+	var revertErr *RevertError
+	require.ErrorAs(t, err, &revertErr)
+	require.Equal(t, "GameNotInProgress", revertErr.Name)
+	require.Equal(t, []interface{}{"not started"}, revertErr.Args)
+}
+
+func TestContractCall_DecodeRevert_UnrecognizedSelector(t *testing.T) {
+	a, err := abi.JSON(strings.NewReader(testRevertABIJSON))
+	require.NoError(t, err)
+	call := NewContractCall(&a, common.Address{}, "foo")
+
+	err = call.DecodeRevert(hexutil.MustDecode("0xdeadbeef"))
+	require.Error(t, err)
+	var revertErr *RevertError
+	require.False(t, errors.As(err, &revertErr))
+}
+
+func TestContractCall_DecodeRevert_TooShortToContainSelector(t *testing.T) {
+	a, err := abi.JSON(strings.NewReader(testRevertABIJSON))
+	require.NoError(t, err)
+	call := NewContractCall(&a, common.Address{}, "foo")
+
+	err = call.DecodeRevert(hexutil.Bytes{0x01, 0x02})
+	require.Error(t, err)
+}